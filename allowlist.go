@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/pkg/errors"
+	"github.com/takaishi/sg_inspector/server"
+)
+
+// ALLOWLIST_KEY_PREFIX namespaces the per-entry Redis keys used to back the
+// allowlist. Each entry is its own key (allowed_sg:{id}) with a Redis TTL
+// attached via EXPIRE, replacing the old untyped allowed_sg list that never
+// expired on its own.
+const ALLOWLIST_KEY_PREFIX = "allowed_sg:"
+
+// RedisAllowlist is the Redis-backed implementation of server.AllowlistStore.
+type RedisAllowlist struct {
+	Client *redis.Client
+}
+
+func NewRedisAllowlist(client *redis.Client) *RedisAllowlist {
+	return &RedisAllowlist{Client: client}
+}
+
+// Add allow-lists id for the given ttl. A ttl of zero means the entry never
+// expires.
+func (a *RedisAllowlist) Add(id string, ttl time.Duration) error {
+	ctx := context.Background()
+	if err := a.Client.Set(ctx, ALLOWLIST_KEY_PREFIX+id, id, ttl).Err(); err != nil {
+		return errors.Wrapf(err, "Failed to set allowlist entry (%s)", id)
+	}
+	return nil
+}
+
+// Remove deletes id from the allowlist, if present.
+func (a *RedisAllowlist) Remove(id string) error {
+	ctx := context.Background()
+	if err := a.Client.Del(ctx, ALLOWLIST_KEY_PREFIX+id).Err(); err != nil {
+		return errors.Wrapf(err, "Failed to delete allowlist entry (%s)", id)
+	}
+	return nil
+}
+
+// List returns every currently allow-listed security group ID along with
+// its remaining TTL.
+func (a *RedisAllowlist) List() ([]server.Entry, error) {
+	ids, err := a.scanIDs()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+	entries := make([]server.Entry, 0, len(ids))
+	for _, id := range ids {
+		ttl, err := a.Client.TTL(ctx, ALLOWLIST_KEY_PREFIX+id).Result()
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to get TTL for allowlist entry (%s)", id)
+		}
+		entries = append(entries, server.Entry{ID: id, TTL: ttl})
+	}
+	return entries, nil
+}
+
+// scanIDs walks allowed_sg:* via SCAN and returns the bare security group
+// IDs (the key prefix stripped off), deduplicated since SCAN's cursor
+// contract allows the same key to be returned more than once in a single
+// walk.
+func (a *RedisAllowlist) scanIDs() ([]string, error) {
+	ctx := context.Background()
+	seen := map[string]bool{}
+	ids := []string{}
+	var cursor uint64
+	for {
+		keys, next, err := a.Client.Scan(ctx, cursor, ALLOWLIST_KEY_PREFIX+"*", 0).Result()
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to scan %s*", ALLOWLIST_KEY_PREFIX)
+		}
+		for _, key := range keys {
+			id := key[len(ALLOWLIST_KEY_PREFIX):]
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+			ids = append(ids, id)
+		}
+		cursor = next
+		if cursor == 0 {
+			break
+		}
+	}
+	return ids, nil
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"os"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/spf13/cobra"
+	"github.com/takaishi/sg_inspector/server"
+)
+
+// NewServeCommand builds the `sg_inspector serve` subcommand, which runs the
+// checker as a long-running service fronted by an embedded HTTP API instead
+// of the usual one-shot batch invocation.
+func NewServeCommand(checker *OpenStackSecurityGroupChecker) *cobra.Command {
+	var addr string
+
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Run sg_inspector as an HTTP service",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			redisURL := "localhost:6379"
+			if os.Getenv("REDIS_URL") != "" {
+				redisURL = os.Getenv("REDIS_URL")
+			}
+			redisClient := redis.NewClient(&redis.Options{Addr: redisURL})
+			allowlist := NewRedisAllowlist(redisClient)
+
+			srv := server.New(allowlist, checker)
+			return srv.ListenAndServe(addr)
+		},
+	}
+	cmd.Flags().StringVar(&addr, "addr", ":8080", "address the HTTP API listens on")
+	return cmd
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/servers"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/layer3/floatingips"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/security/groups"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/ports"
+)
+
+func TestInstanceInfoForSGCorrelatesPortServerAndFIP(t *testing.T) {
+	sg := groups.SecGroup{ID: "sg-1"}
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	sgPorts := []ports.Port{
+		{ID: "port-1", DeviceID: "server-1", SecurityGroups: []string{"sg-other"}},
+		{ID: "port-2", DeviceID: "server-1", SecurityGroups: []string{"sg-1"}},
+	}
+	fips := []floatingips.FloatingIP{
+		{PortID: "port-2", FloatingIP: "203.0.113.10"},
+	}
+	serversByID := map[string]servers.Server{
+		"server-1": {Name: "web-1", UserID: "user-1", Created: created},
+	}
+
+	instance, floatingIP, owner, createdAt := instanceInfoForSG(sg, sgPorts, fips, serversByID)
+
+	if instance != "web-1" {
+		t.Errorf("instance = %q, want %q", instance, "web-1")
+	}
+	if floatingIP != "203.0.113.10" {
+		t.Errorf("floatingIP = %q, want %q", floatingIP, "203.0.113.10")
+	}
+	if owner != "user-1" {
+		t.Errorf("owner = %q, want %q", owner, "user-1")
+	}
+	if want := created.Local().String(); createdAt != want {
+		t.Errorf("created = %q, want %q", createdAt, want)
+	}
+}
+
+func TestInstanceInfoForSGNoMatchingPort(t *testing.T) {
+	sg := groups.SecGroup{ID: "sg-1"}
+	sgPorts := []ports.Port{
+		{ID: "port-1", DeviceID: "server-1", SecurityGroups: []string{"sg-other"}},
+	}
+	serversByID := map[string]servers.Server{
+		"server-1": {Name: "web-1"},
+	}
+
+	instance, floatingIP, owner, createdAt := instanceInfoForSG(sg, sgPorts, nil, serversByID)
+
+	if instance != "" || floatingIP != "" || owner != "" || createdAt != "" {
+		t.Errorf("instanceInfoForSG = (%q, %q, %q, %q), want all empty when no port references sg", instance, floatingIP, owner, createdAt)
+	}
+}
+
+func TestInstanceInfoForSGPortWithoutFloatingIP(t *testing.T) {
+	sg := groups.SecGroup{ID: "sg-1"}
+	sgPorts := []ports.Port{
+		{ID: "port-1", DeviceID: "server-1", SecurityGroups: []string{"sg-1"}},
+	}
+	serversByID := map[string]servers.Server{
+		"server-1": {Name: "web-1", UserID: "user-1"},
+	}
+
+	instance, floatingIP, owner, _ := instanceInfoForSG(sg, sgPorts, nil, serversByID)
+
+	if instance != "web-1" || owner != "user-1" {
+		t.Errorf("instanceInfoForSG instance/owner = (%q, %q), want (%q, %q)", instance, owner, "web-1", "user-1")
+	}
+	if floatingIP != "" {
+		t.Errorf("floatingIP = %q, want empty when no FloatingIP references the port", floatingIP)
+	}
+}
+
+func TestInstanceInfoForSGSkipsPortWithUnknownDevice(t *testing.T) {
+	sg := groups.SecGroup{ID: "sg-1"}
+	sgPorts := []ports.Port{
+		{ID: "port-1", DeviceID: "missing-server", SecurityGroups: []string{"sg-1"}},
+	}
+	serversByID := map[string]servers.Server{}
+
+	instance, _, _, _ := instanceInfoForSG(sg, sgPorts, nil, serversByID)
+
+	if instance != "" {
+		t.Errorf("instance = %q, want empty when the port's device isn't in serversByID", instance)
+	}
+}
@@ -0,0 +1,233 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/pkg/errors"
+	"github.com/slack-go/slack"
+)
+
+// Finding is a single offending security group, independent of how it ends
+// up being reported. Both the full-open and policy-match passes append to a
+// []Finding so the same results can fan out to more than one Notifier.
+type Finding struct {
+	Kind       string `json:"kind"` // "full_open" or "policy"
+	SGID       string `json:"sg_id"`
+	Tenant     string `json:"tenant"`
+	Name       string `json:"name"`
+	Region     string `json:"region"`
+	Rule       string `json:"rule"`
+	Instance   string `json:"instance,omitempty"`
+	FloatingIP string `json:"floating_ip,omitempty"`
+	Owner      string `json:"owner,omitempty"`
+	Created    string `json:"created,omitempty"`
+	Severity   string `json:"severity"`
+}
+
+// Notifier is a sink findings are reported to. Flush is called once per
+// audit pass after every finding has been handed to Notify, so batching
+// sinks (Slack's prefix/suffix messages) can send one message instead of
+// one per finding.
+type Notifier interface {
+	Notify(ctx context.Context, finding Finding) error
+	Flush(ctx context.Context) error
+}
+
+// SlackNotifier reproduces the original prefix-message / one-attachment-per-
+// finding / suffix-message behavior, buffering findings until Flush.
+type SlackNotifier struct {
+	Client    *slack.Client
+	Channel   string
+	Username  string
+	IconEmoji string
+	Prefix    string
+	Suffix    string
+
+	pending []Finding
+}
+
+func (n *SlackNotifier) Notify(ctx context.Context, finding Finding) error {
+	n.pending = append(n.pending, finding)
+	return nil
+}
+
+func (n *SlackNotifier) Flush(ctx context.Context) error {
+	if len(n.pending) == 0 {
+		return nil
+	}
+	defer func() { n.pending = nil }()
+
+	params := slack.PostMessageParameters{Username: n.Username, IconEmoji: n.IconEmoji}
+	if err := postMessage(n.Client, n.Channel, n.Prefix, nil, params); err != nil {
+		return errors.Wrapf(err, "Failed to post prefix message")
+	}
+
+	for _, finding := range n.pending {
+		attachment := slack.Attachment{Color: "#ff6347", Fields: findingToAttachmentFields(finding)}
+		if err := postMessage(n.Client, n.Channel, "", []slack.Attachment{attachment}, params); err != nil {
+			return errors.Wrapf(err, "Failed to post attachment")
+		}
+	}
+
+	if err := postMessage(n.Client, n.Channel, n.Suffix, nil, params); err != nil {
+		return errors.Wrapf(err, "Failed to post suffix message")
+	}
+	return nil
+}
+
+func findingToAttachmentFields(finding Finding) []slack.AttachmentField {
+	fields := []slack.AttachmentField{
+		{Title: "Region", Value: finding.Region, Short: true},
+		{Title: "Tenant", Value: finding.Tenant, Short: true},
+		{Title: "ID", Value: finding.SGID, Short: true},
+		{Title: "Name", Value: finding.Name, Short: true},
+		{Title: "Rule", Value: finding.Rule},
+	}
+	if finding.Instance != "" {
+		fields = append(fields,
+			slack.AttachmentField{Title: "Instance", Value: finding.Instance, Short: true},
+			slack.AttachmentField{Title: "Floating IP", Value: finding.FloatingIP, Short: true},
+			slack.AttachmentField{Title: "Owner", Value: finding.Owner, Short: true},
+			slack.AttachmentField{Title: "Created", Value: finding.Created, Short: true},
+		)
+	}
+	return fields
+}
+
+// WebhookNotifier POSTs each audit pass's findings as a single JSON array to
+// URL, HMAC-signing the body with Secret when set so the receiver can
+// authenticate the request.
+type WebhookNotifier struct {
+	URL    string
+	Secret string
+	Client *http.Client
+
+	pending []Finding
+}
+
+func (n *WebhookNotifier) Notify(ctx context.Context, finding Finding) error {
+	n.pending = append(n.pending, finding)
+	return nil
+}
+
+func (n *WebhookNotifier) Flush(ctx context.Context) error {
+	if len(n.pending) == 0 {
+		return nil
+	}
+	defer func() { n.pending = nil }()
+
+	body, err := json.Marshal(n.pending)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if n.Secret != "" {
+		mac := hmac.New(sha256.New, []byte(n.Secret))
+		mac.Write(body)
+		req.Header.Set("X-Signature-256", hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	client := n.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to post webhook to %s", n.URL)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("Failed to post webhook to %s: unexpected status %s", n.URL, resp.Status)
+	}
+	return nil
+}
+
+// PagerDutyNotifier triggers a PagerDuty Events API v2 event per finding,
+// deduplicated on the security group ID so repeated audits update the same
+// incident instead of opening a new one each time.
+type PagerDutyNotifier struct {
+	RoutingKey string
+	Client     *http.Client
+
+	// eventsURL overrides pagerDutyEventsURL; only ever set by tests.
+	eventsURL string
+}
+
+const pagerDutyEventsURL = "https://events.pagerduty.com/v2/enqueue"
+
+func (n *PagerDutyNotifier) Notify(ctx context.Context, finding Finding) error {
+	payload := map[string]interface{}{
+		"routing_key":  n.RoutingKey,
+		"event_action": "trigger",
+		"dedup_key":    finding.SGID,
+		"payload": map[string]interface{}{
+			"summary":        fmt.Sprintf("Security group %s (%s) in %s is exposed", finding.Name, finding.SGID, finding.Tenant),
+			"source":         finding.Tenant,
+			"severity":       finding.Severity,
+			"custom_details": finding,
+		},
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	url := n.eventsURL
+	if url == "" {
+		url = pagerDutyEventsURL
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := n.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to trigger PagerDuty event for %s", finding.SGID)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("Failed to trigger PagerDuty event for %s: unexpected status %s", finding.SGID, resp.Status)
+	}
+	return nil
+}
+
+// Flush is a no-op: the Events API has no batch endpoint, so every finding
+// is already sent by Notify.
+func (n *PagerDutyNotifier) Flush(ctx context.Context) error {
+	return nil
+}
+
+// StdoutNotifier writes one JSON line per finding, for cron/CI use where
+// there's no chat or paging integration to send to.
+type StdoutNotifier struct{}
+
+func (StdoutNotifier) Notify(ctx context.Context, finding Finding) error {
+	b, err := json.Marshal(finding)
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(b))
+	return nil
+}
+
+func (StdoutNotifier) Flush(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWebhookNotifierSignsBody(t *testing.T) {
+	const secret = "s3cr3t"
+	var gotBody []byte
+	var gotSignature string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotBody, _ = ioutil.ReadAll(r.Body)
+		gotSignature = r.Header.Get("X-Signature-256")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	n := &WebhookNotifier{URL: ts.URL, Secret: secret}
+	finding := Finding{Kind: "full_open", SGID: "sg-1", Tenant: "team-a"}
+	if err := n.Notify(context.Background(), finding); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+	if err := n.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+
+	var got []Finding
+	if err := json.Unmarshal(gotBody, &got); err != nil {
+		t.Fatalf("unmarshal posted body: %v", err)
+	}
+	if len(got) != 1 || got[0].SGID != finding.SGID {
+		t.Fatalf("posted body = %+v, want [%+v]", got, finding)
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(gotBody)
+	want := hex.EncodeToString(mac.Sum(nil))
+	if gotSignature != want {
+		t.Errorf("X-Signature-256 = %q, want %q", gotSignature, want)
+	}
+}
+
+func TestWebhookNotifierOmitsSignatureWithoutSecret(t *testing.T) {
+	var gotSignature string
+	sawSignatureHeader := false
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotSignature, sawSignatureHeader = r.Header.Get("X-Signature-256"), r.Header.Get("X-Signature-256") != ""
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	n := &WebhookNotifier{URL: ts.URL}
+	if err := n.Notify(context.Background(), Finding{SGID: "sg-1"}); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+	if err := n.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+	if sawSignatureHeader {
+		t.Errorf("X-Signature-256 = %q, want no header when Secret is unset", gotSignature)
+	}
+}
+
+func TestPagerDutyNotifierPayload(t *testing.T) {
+	const routingKey = "routing-key-123"
+	var gotPayload map[string]interface{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&gotPayload); err != nil {
+			t.Errorf("decode request body: %v", err)
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer ts.Close()
+
+	n := &PagerDutyNotifier{RoutingKey: routingKey, eventsURL: ts.URL}
+	finding := Finding{Kind: "full_open", SGID: "sg-1", Tenant: "team-a", Name: "default", Severity: "critical"}
+	if err := n.Notify(context.Background(), finding); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+
+	if gotPayload["routing_key"] != routingKey {
+		t.Errorf("routing_key = %v, want %v", gotPayload["routing_key"], routingKey)
+	}
+	if gotPayload["event_action"] != "trigger" {
+		t.Errorf("event_action = %v, want %q", gotPayload["event_action"], "trigger")
+	}
+	if gotPayload["dedup_key"] != finding.SGID {
+		t.Errorf("dedup_key = %v, want %v", gotPayload["dedup_key"], finding.SGID)
+	}
+	details, ok := gotPayload["payload"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("payload.payload = %T, want map[string]interface{}", gotPayload["payload"])
+	}
+	if details["severity"] != finding.Severity {
+		t.Errorf("payload.severity = %v, want %v", details["severity"], finding.Severity)
+	}
+	if details["source"] != finding.Tenant {
+		t.Errorf("payload.source = %v, want %v", details["source"], finding.Tenant)
+	}
+}
@@ -12,10 +12,13 @@ import (
 	"os"
 	"regexp"
 	"strconv"
+	"sync"
+	"time"
 
 	"github.com/go-redis/redis/v8"
 	"github.com/gophercloud/gophercloud"
 	"github.com/gophercloud/gophercloud/openstack"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/servers"
 	"github.com/gophercloud/gophercloud/openstack/identity/v3/projects"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/layer3/floatingips"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/security/groups"
@@ -26,10 +29,9 @@ import (
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"github.com/slack-go/slack"
+	"golang.org/x/sync/errgroup"
 )
 
-const REDIS_KEY = "allowed_sg"
-
 type OpenStackSecurityGroupChecker struct {
 	Cfg         Config
 	SlackClient *slack.Client
@@ -38,10 +40,62 @@ type OpenStackSecurityGroupChecker struct {
 	CACert      string
 	Cert        string
 	Key         string
-	Attachments []slack.Attachment
+	Findings    []Finding
 	Projects    []projects.Project
+
+	// DecisionLogger records every policy evaluation. Defaults to
+	// StdoutDecisionLogger when nil.
+	DecisionLogger DecisionLogger
+
+	// policyCache is a pointer so copying a checker (Run's per-region copy)
+	// shares one cache instead of copying a mutex by value.
+	policyCache *policySourceCache
+}
+
+type policySourceCache struct {
+	mu      sync.Mutex
+	sources map[string]PolicySource
+}
+
+// policySourceFor returns the cached PolicySource for policy, building and
+// caching one on first use so the prepared query (and, for bundles, the
+// ETag) survives across Run() calls instead of being rebuilt every audit.
+func (checker *OpenStackSecurityGroupChecker) policySourceFor(policy Policy) PolicySource {
+	if checker.policyCache == nil {
+		checker.policyCache = &policySourceCache{}
+	}
+	checker.policyCache.mu.Lock()
+	defer checker.policyCache.mu.Unlock()
+
+	if checker.policyCache.sources == nil {
+		checker.policyCache.sources = map[string]PolicySource{}
+	}
+	key := policy.BundleURL + "|" + policy.Policy + "|" + policy.Data
+	if source, ok := checker.policyCache.sources[key]; ok {
+		return source
+	}
+	source := policySourceFor(policy)
+	checker.policyCache.sources[key] = source
+	return source
+}
+
+func (checker *OpenStackSecurityGroupChecker) decisionLogger() DecisionLogger {
+	if checker.DecisionLogger != nil {
+		return checker.DecisionLogger
+	}
+	return StdoutDecisionLogger{}
+}
+
+// LastFindings returns the findings produced by the most recent Run, so the
+// HTTP API can serve GET /v1/findings/latest without re-running an audit.
+func (checker *OpenStackSecurityGroupChecker) LastFindings() interface{} {
+	return checker.Findings
 }
 
+// Run fans out one goroutine per configured region, each performing its own
+// authenticate+fetch+evaluate pass, bounded by Cfg.MaxConcurrency. Findings
+// from every region are aggregated (under a mutex) into checker.Findings
+// once all regions finish.
 func (checker *OpenStackSecurityGroupChecker) Run() (err error) {
 	redisURL := "localhost:6379"
 
@@ -54,16 +108,74 @@ func (checker *OpenStackSecurityGroupChecker) Run() (err error) {
 			Password: "",
 			DB:       0,
 		})
-	len, err := redisClient.LLen(context.Background(), REDIS_KEY).Result()
+	allowlist := NewRedisAllowlist(redisClient)
+	entries, err := allowlist.List()
 	if err != nil {
 		return err
 	}
-	allowed_sg, err := redisClient.LRange(context.Background(), REDIS_KEY, 0, len).Result()
-	if err != nil {
-		return err
+	allowed_sg := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		allowed_sg = append(allowed_sg, entry.ID)
 	}
 	logrus.Infof("Temporary allowed security groups: %+v\n", allowed_sg)
 
+	if checker.policyCache == nil {
+		checker.policyCache = &policySourceCache{}
+	}
+
+	regions := checker.Cfg.Regions
+	if len(regions) == 0 {
+		regions = []string{checker.RegionName}
+	}
+
+	maxConcurrency := checker.Cfg.MaxConcurrency
+	if maxConcurrency <= 0 {
+		maxConcurrency = len(regions)
+	}
+
+	var (
+		mu       sync.Mutex
+		findings []Finding
+	)
+	eg, ctx := errgroup.WithContext(context.Background())
+	eg.SetLimit(maxConcurrency)
+	for _, region := range regions {
+		region := region
+		eg.Go(func() error {
+			regionChecker := *checker
+			regionChecker.RegionName = region
+			regionChecker.Findings = nil
+			// Cfg.Rules is a slice: the shallow copy above still shares its
+			// backing array with checker.Cfg.Rules and every other region's
+			// copy, so each goroutine gets its own. runRegion then replaces
+			// it with the copy resolveRuleTenantIDs returns, leaving the
+			// shared array untouched either way.
+			regionChecker.Cfg.Rules = append([]Rule(nil), checker.Cfg.Rules...)
+
+			if err := regionChecker.runRegion(ctx, allowed_sg); err != nil {
+				return errors.Wrapf(err, "Failed to audit region %s", region)
+			}
+
+			mu.Lock()
+			findings = append(findings, regionChecker.Findings...)
+			mu.Unlock()
+			return nil
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		return err
+	}
+
+	checker.Findings = findings
+	return nil
+}
+
+// runRegion performs a single region's audit: it authenticates against that
+// region, fetches projects/ports/FIPs/security groups/servers in parallel
+// (they're independent of one another), then evaluates the full-open and
+// policy checks and posts a warning per check, same as the pre-multi-region
+// behavior.
+func (checker *OpenStackSecurityGroupChecker) runRegion(ctx context.Context, allowed_sg []string) (err error) {
 	existNoguardSG := false
 	eo := gophercloud.EndpointOpts{Region: checker.RegionName}
 	client, err := checker.authenticate(checker.AuthOptions, checker.CACert, checker.Cert, checker.Key)
@@ -71,37 +183,48 @@ func (checker *OpenStackSecurityGroupChecker) Run() (err error) {
 		return errors.Wrapf(err, "Failed to authenticate OpenStack API")
 	}
 
-	checker.Projects, err = checker.fetchProjects(client, eo)
-	if err != nil {
+	var (
+		portList       []ports.Port
+		fips           []floatingips.FloatingIP
+		securityGroups []groups.SecGroup
+		serversByID    map[string]servers.Server
+	)
+	fetchGroup, _ := errgroup.WithContext(ctx)
+	fetchGroup.Go(func() (err error) {
+		checker.Projects, err = checker.fetchProjects(client, eo)
 		return errors.Wrapf(err, "Failed to fetch projects")
-	}
-
-	for i, rule := range checker.Cfg.Rules {
-		for _, p := range checker.Projects {
-			if rule.Tenant == p.Name {
-				checker.Cfg.Rules[i].TenantID = p.ID
-			}
-		}
-	}
-	ports, err := checker.fetchPorts(client, eo)
-	if err != nil {
+	})
+	fetchGroup.Go(func() (err error) {
+		portList, err = checker.fetchPorts(client, eo)
 		return errors.Wrapf(err, "Failed to fetch ports")
-	}
-
-	fips, err := checker.fetchFloatingIPS(client, eo)
-	if err != nil {
+	})
+	fetchGroup.Go(func() (err error) {
+		fips, err = checker.fetchFloatingIPS(client, eo)
 		return errors.Wrapf(err, "Failed to fetch fips")
+	})
+	fetchGroup.Go(func() (err error) {
+		securityGroups, err = checker.fetchSecurityGroups(client, eo)
+		return errors.Wrapf(err, "Failed to fetch security groups")
+	})
+	fetchGroup.Go(func() (err error) {
+		serversByID, err = checker.fetchServers(client, eo)
+		return errors.Wrapf(err, "Failed to fetch servers")
+	})
+	if err := fetchGroup.Wait(); err != nil {
+		return err
 	}
 
-	securityGroups, err := checker.fetchSecurityGroups(client, eo)
-	if err != nil {
-		return errors.Wrapf(err, "Failed to security groups")
-	}
+	checker.Cfg.Rules = resolveRuleTenantIDs(checker.Cfg.Rules, checker.Projects)
 
-	logrus.Info("Start to find security group is allowed to access from any.")
+	logrus.Infof("[%s] Start to find security group is allowed to access from any.", checker.RegionName)
 
+	// checker.Findings accumulates across both passes (it's what Run()
+	// aggregates into the checker returned by LastFindings), so each pass
+	// slices out only the findings it just appended instead of resetting
+	// the field, which would drop the other pass's findings.
+	fullOpenStart := len(checker.Findings)
 	for _, sg := range securityGroups {
-		isFullOpen, err := checker.isFullOpen(sg, ports, fips, allowed_sg)
+		isFullOpen, err := checker.isFullOpen(sg, portList, fips, serversByID, allowed_sg)
 		if err != nil {
 			return err
 		}
@@ -109,49 +232,38 @@ func (checker *OpenStackSecurityGroupChecker) Run() (err error) {
 			existNoguardSG = true
 		}
 	}
+	fullOpenFindings := checker.Findings[fullOpenStart:]
 
 	if existNoguardSG {
 		if !checker.Cfg.DryRun {
-			err := checker.postWarning(checker.Attachments, checker.Cfg.PrefixMessage, checker.Cfg.SuffixMessage)
-			if err != nil {
-				return errors.Wrapf(err, "Failed to post warning")
+			if err := checker.notify(ctx, fullOpenFindings, checker.Cfg.PrefixMessage, checker.Cfg.SuffixMessage); err != nil {
+				return errors.Wrapf(err, "Failed to notify findings")
 			}
 		}
 
-		logrus.Info("Security group that allowed to access from any is found.")
+		logrus.Infof("[%s] Security group that allowed to access from any is found.", checker.RegionName)
 
 	} else {
-		logrus.Info("No security group that allowed to access from any is found.")
+		logrus.Infof("[%s] No security group that allowed to access from any is found.", checker.RegionName)
 	}
 
-	checker.Attachments = []slack.Attachment{}
-
-	logrus.Info("Start to find security group don't match policy.")
+	logrus.Infof("[%s] Start to find security group don't match policy.", checker.RegionName)
 
 	for _, policy := range checker.Cfg.Policies {
-		paths := []string{}
-		if policy.Policy != "" {
-			paths = append(paths, policy.Policy)
-		}
-		if policy.Data != "" {
-			paths = append(paths, policy.Data)
-		}
-		r := rego.New(
-			rego.Query("x = data.example.allow"),
-			rego.Load(paths, nil),
-		)
-
-		query, err := r.PrepareForEval(context.Background())
+		source := checker.policySourceFor(policy)
+		query, err := source.PreparedQuery(ctx)
 		if err != nil {
-			return err
+			return errors.Wrapf(err, "Failed to prepare policy query")
 		}
+
+		policyStart := len(checker.Findings)
 		existsSGMatchedPolicy := false
 		for _, sg := range securityGroups {
 			if contain(allowed_sg, sg.ID) {
 				logrus.Info("許可済みのSGなのでSlackに警告メッセージは流さない")
 				continue
 			}
-			match, err := checker.matchPolicy(query, sg)
+			match, err := checker.matchPolicy(query, sg, serversByID, portList, fips, source.Revision())
 			if err != nil {
 				return err
 			}
@@ -159,17 +271,17 @@ func (checker *OpenStackSecurityGroupChecker) Run() (err error) {
 				existsSGMatchedPolicy = true
 			}
 		}
+		policyFindings := checker.Findings[policyStart:]
 
 		if existsSGMatchedPolicy {
 			if !checker.Cfg.DryRun {
-				err := checker.postWarning(checker.Attachments, policy.PrefixMessage, policy.SuffixMessage)
-				if err != nil {
-					return errors.Wrapf(err, "Failed to post warning")
+				if err := checker.notify(ctx, policyFindings, policy.PrefixMessage, policy.SuffixMessage); err != nil {
+					return errors.Wrapf(err, "Failed to notify findings")
 				}
 			}
-			logrus.Info("Security group that match policy is found.")
+			logrus.Infof("[%s] Security group that match policy is found.", checker.RegionName)
 		} else {
-			logrus.Info("No security group that match policy is found.")
+			logrus.Infof("[%s] No security group that match policy is found.", checker.RegionName)
 		}
 	}
 	return nil
@@ -184,35 +296,61 @@ func contain(s []string, e string) bool {
 	return false
 }
 
-func (checker *OpenStackSecurityGroupChecker) postWarning(attachments []slack.Attachment, prefix string, suffix string) error {
-	params := slack.PostMessageParameters{
-		Username:  checker.Cfg.Username,
-		IconEmoji: checker.Cfg.IconEmoji,
-	}
-	err := postMessage(checker.SlackClient, checker.Cfg.SlackChannel, prefix, nil, params)
-	if err != nil {
-		return errors.Wrapf(err, "Failed to post prefix message")
-	}
-
-	for _, item := range attachments {
-		params := slack.PostMessageParameters{
-			Username:  checker.Cfg.Username,
-			IconEmoji: checker.Cfg.IconEmoji,
-		}
-		attachments := []slack.Attachment{
-			item,
+// notify fans findings out to every configured Notifier, falling back to
+// posting directly to Slack (the pre-Notifier behavior) when Cfg.Notifiers
+// is empty.
+func (checker *OpenStackSecurityGroupChecker) notify(ctx context.Context, findings []Finding, prefix string, suffix string) error {
+	for _, notifier := range checker.notifiersFor(prefix, suffix) {
+		for _, finding := range findings {
+			if err := notifier.Notify(ctx, finding); err != nil {
+				return errors.Wrapf(err, "Failed to notify finding (%s)", finding.SGID)
+			}
 		}
-		err = postMessage(checker.SlackClient, checker.Cfg.SlackChannel, "", attachments, params)
-		if err != nil {
-			return errors.Wrapf(err, "Failed to post attachments")
+		if err := notifier.Flush(ctx); err != nil {
+			return errors.Wrapf(err, "Failed to flush notifier")
 		}
 	}
-	err = postMessage(checker.SlackClient, checker.Cfg.SlackChannel, suffix, nil, params)
-	if err != nil {
-		return errors.Wrapf(err, "Failed to post suffix message")
+	return nil
+}
+
+// notifiersFor builds the Notifier set for a single full-open or policy
+// pass. prefix/suffix only apply to Slack's bracketing messages; other
+// sinks ignore them. A fresh Slack notifier is built per call since prefix
+// and suffix differ between the full-open pass and each policy.
+func (checker *OpenStackSecurityGroupChecker) notifiersFor(prefix, suffix string) []Notifier {
+	if len(checker.Cfg.Notifiers) == 0 {
+		return []Notifier{checker.slackNotifier(checker.Cfg.SlackChannel, prefix, suffix)}
+	}
+
+	notifiers := make([]Notifier, 0, len(checker.Cfg.Notifiers))
+	for _, nc := range checker.Cfg.Notifiers {
+		switch nc.Kind {
+		case "webhook":
+			notifiers = append(notifiers, &WebhookNotifier{URL: nc.WebhookURL, Secret: nc.WebhookSecret})
+		case "pagerduty":
+			notifiers = append(notifiers, &PagerDutyNotifier{RoutingKey: nc.PagerDutyRoutingKey})
+		case "stdout":
+			notifiers = append(notifiers, StdoutNotifier{})
+		default:
+			channel := nc.SlackChannel
+			if channel == "" {
+				channel = checker.Cfg.SlackChannel
+			}
+			notifiers = append(notifiers, checker.slackNotifier(channel, prefix, suffix))
+		}
 	}
+	return notifiers
+}
 
-	return nil
+func (checker *OpenStackSecurityGroupChecker) slackNotifier(channel, prefix, suffix string) *SlackNotifier {
+	return &SlackNotifier{
+		Client:    checker.SlackClient,
+		Channel:   channel,
+		Username:  checker.Cfg.Username,
+		IconEmoji: checker.Cfg.IconEmoji,
+		Prefix:    prefix,
+		Suffix:    suffix,
+	}
 }
 
 func postMessage(api *slack.Client, channel string, text string, attachments []slack.Attachment, params slack.PostMessageParameters) error {
@@ -232,9 +370,57 @@ func getProjectNameFromID(id string, ps []projects.Project) (string, error) {
 	return "", fmt.Errorf("Not found project: %s", id)
 }
 
+// wildcardRemotes are the RemoteIPPrefix values that mean "the whole
+// internet" for both address families.
+var wildcardRemotes = []string{"0.0.0.0/0", "::/0"}
+
+// defaultFlaggedProtocols is used when Cfg.Protocols is unset. A Cfg.Protocols
+// pointer to an empty slice means "any protocol", since a nil field alone
+// can't be told apart from "not configured".
+var defaultFlaggedProtocols = []string{"tcp", "udp", "icmp", "icmpv6"}
+
+// isWildcardRemote reports whether prefix means "open to the whole
+// internet" for either IPv4 or IPv6.
+func isWildcardRemote(prefix string) bool {
+	return contains(wildcardRemotes, prefix)
+}
+
+// flaggedProtocols returns the protocols isFullOpen should treat as
+// dangerous when wildcard-exposed, and whether every protocol (including an
+// empty/"any" rule.Protocol) should be flagged.
+func (checker *OpenStackSecurityGroupChecker) flaggedProtocols() (protocols []string, any bool) {
+	if checker.Cfg.Protocols == nil {
+		return defaultFlaggedProtocols, false
+	}
+	if len(*checker.Cfg.Protocols) == 0 {
+		return nil, true
+	}
+	return *checker.Cfg.Protocols, false
+}
+
+// resolveRuleTenantIDs returns a copy of rules with TenantID filled in from
+// projects by matching Tenant name, leaving rules itself untouched. It's a
+// pure function (rather than the mutate-in-place loop it replaced) so
+// concurrent region goroutines resolving against different project lists
+// never share, and race on, the same backing array.
+func resolveRuleTenantIDs(ruleList []Rule, projects []projects.Project) []Rule {
+	resolved := append([]Rule(nil), ruleList...)
+	for i, rule := range resolved {
+		for _, p := range projects {
+			if rule.Tenant == p.Name {
+				resolved[i].TenantID = p.ID
+			}
+		}
+	}
+	return resolved
+}
+
 func matchAllowdRule(allowdRules []Rule, sg groups.SecGroup, rule rules.SecGroupRule) bool {
 	for _, allowdRule := range allowdRules {
 		if allowdRule.TenantID == sg.TenantID && allowdRule.SG == sg.Name {
+			if allowdRule.Protocol != "" && allowdRule.Protocol != rule.Protocol {
+				continue
+			}
 			r := regexp.MustCompile(`(\d*)-(\d*)`)
 			for _, port := range allowdRule.Port {
 				if r.MatchString(port) {
@@ -382,7 +568,61 @@ func (checker *OpenStackSecurityGroupChecker) fetchFloatingIPS(client *gopherclo
 	return
 }
 
-func (checker *OpenStackSecurityGroupChecker) isFullOpen(sg groups.SecGroup, ports []ports.Port, fips []floatingips.FloatingIP, allowed_sg []string) (bool, error) {
+// instanceInfoForSG correlates sg to the VM(s) using it via the ports that
+// reference it, so a Finding can report "VM X in tenant Y at FIP Z is
+// exposed" instead of just naming the security group. Only the first
+// matching instance is returned; a SG shared by many VMs is rare enough in
+// practice that callers haven't needed more than that.
+func instanceInfoForSG(sg groups.SecGroup, sgPorts []ports.Port, fips []floatingips.FloatingIP, serversByID map[string]servers.Server) (instance, floatingIP, owner, created string) {
+	for _, port := range sgPorts {
+		for _, sgid := range port.SecurityGroups {
+			if sgid != sg.ID {
+				continue
+			}
+			server, ok := serversByID[port.DeviceID]
+			if !ok {
+				continue
+			}
+
+			for _, fip := range fips {
+				if fip.PortID == port.ID {
+					floatingIP = fip.FloatingIP
+					break
+				}
+			}
+			return server.Name, floatingIP, server.UserID, server.Created.Local().String()
+		}
+	}
+	return "", "", "", ""
+}
+
+// fetchServers lists every server across all tenants and indexes them by ID
+// so offending security groups can be correlated back to the VM(s) using
+// them.
+func (checker *OpenStackSecurityGroupChecker) fetchServers(client *gophercloud.ProviderClient, eo gophercloud.EndpointOpts) (map[string]servers.Server, error) {
+	computeClient, err := openstack.NewComputeV2(client, eo)
+	if err != nil {
+		return nil, err
+	}
+
+	results := map[string]servers.Server{}
+	err = servers.List(computeClient, servers.ListOpts{AllTenants: true}).EachPage(func(page pagination.Page) (bool, error) {
+		extracted, err := servers.ExtractServers(page)
+		if err != nil {
+			return false, err
+		}
+		for _, server := range extracted {
+			results[server.ID] = server
+		}
+		return true, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+func (checker *OpenStackSecurityGroupChecker) isFullOpen(sg groups.SecGroup, ports []ports.Port, fips []floatingips.FloatingIP, serversByID map[string]servers.Server, allowed_sg []string) (bool, error) {
 	isFullOpen := false
 
 	ignorePort := true
@@ -418,8 +658,9 @@ IGNOREPORT:
 		return false, nil
 	}
 
+	flagged, anyProtocol := checker.flaggedProtocols()
 	for _, rule := range sg.Rules {
-		if rule.RemoteIPPrefix == "0.0.0.0/0" && rule.Protocol == "tcp" && rule.Direction == "ingress" {
+		if isWildcardRemote(rule.RemoteIPPrefix) && (anyProtocol || contains(flagged, rule.Protocol)) && rule.Direction == "ingress" {
 			if !matchAllowdRule(checker.Cfg.Rules, sg, rule) {
 				if contain(allowed_sg, sg.ID) {
 					logrus.Info("許可済みのSGなのでSlackに警告メッセージは流さない")
@@ -436,17 +677,20 @@ IGNOREPORT:
 				fmt.Printf("tenant = \"%s\"\n", projectName)
 				fmt.Printf("sg = \"%s\"\n", sg.Name)
 
-				fields := []slack.AttachmentField{
-					{Title: "Tenant", Value: projectName},
-					{Title: "ID", Value: sg.ID},
-					{Title: "Name", Value: sg.Name},
-					{Title: "PortRange", Value: fmt.Sprintf("%d-%d", rule.PortRangeMin, rule.PortRangeMax)},
-				}
-				attachment := slack.Attachment{
-					Color:  "#ff6347",
-					Fields: fields,
-				}
-				checker.Attachments = append(checker.Attachments, attachment)
+				instance, floatingIP, owner, created := instanceInfoForSG(sg, ports, fips, serversByID)
+				checker.Findings = append(checker.Findings, Finding{
+					Kind:       "full_open",
+					SGID:       sg.ID,
+					Tenant:     projectName,
+					Name:       sg.Name,
+					Region:     checker.RegionName,
+					Rule:       fmt.Sprintf("%s %s-%d-%d", rule.Protocol, rule.RemoteIPPrefix, rule.PortRangeMin, rule.PortRangeMax),
+					Instance:   instance,
+					FloatingIP: floatingIP,
+					Owner:      owner,
+					Created:    created,
+					Severity:   "critical",
+				})
 			}
 		}
 	}
@@ -454,7 +698,7 @@ IGNOREPORT:
 	return isFullOpen, nil
 }
 
-func (checker *OpenStackSecurityGroupChecker) matchPolicy(query rego.PreparedEvalQuery, sg groups.SecGroup) (bool, error) {
+func (checker *OpenStackSecurityGroupChecker) matchPolicy(query rego.PreparedEvalQuery, sg groups.SecGroup, serversByID map[string]servers.Server, ports []ports.Port, fips []floatingips.FloatingIP, bundleRevision string) (bool, error) {
 	match := false
 	ctx := context.Background()
 	var input interface{}
@@ -478,8 +722,17 @@ func (checker *OpenStackSecurityGroupChecker) matchPolicy(query rego.PreparedEva
 	if err != nil {
 		return match, err
 	}
-	if len(rs) > 0 && rs[0].Bindings["x"].(bool) {
-		match = true
+	match = len(rs) > 0 && rs[0].Bindings["x"].(bool)
+	if logErr := checker.decisionLogger().Log(ctx, DecisionLogEntry{
+		Timestamp:      time.Now(),
+		Input:          jsonData,
+		Decision:       match,
+		BundleRevision: bundleRevision,
+	}); logErr != nil {
+		logrus.WithError(logErr).Warn("Failed to emit decision log entry")
+	}
+
+	if match {
 		projectName, err := getProjectNameFromID(sg.TenantID, checker.Projects)
 		if err != nil {
 			err = nil
@@ -488,25 +741,26 @@ func (checker *OpenStackSecurityGroupChecker) matchPolicy(query rego.PreparedEva
 		fmt.Printf("tenant = \"%s\"\n", projectName)
 		fmt.Printf("sg = \"%s\"\n", sg.Name)
 		fmt.Printf("created = \"%s\"\n", sg.CreatedAt.Local())
-		fields := []slack.AttachmentField{
-			{Title: "Name", Value: sg.Name},
-			{Title: "Tenant", Value: projectName, Short: true},
-			{Title: "ID", Value: sg.ID, Short: true},
-			{Title: "Created", Value: sg.CreatedAt.Local().String()},
-		}
-		value := ""
+
+		rulesDescription := ""
 		for _, rule := range sg.Rules {
-			value += fmt.Sprintf("%s, IP Range: %s, Port Range: %s\n", rule.Direction, rule.RemoteIPPrefix, fmt.Sprintf("%d-%d", rule.PortRangeMin, rule.PortRangeMax))
-		}
-		fields = append(fields, slack.AttachmentField{
-			Title: "Rules",
-			Value: value,
+			rulesDescription += fmt.Sprintf("%s, IP Range: %s, Port Range: %s\n", rule.Direction, rule.RemoteIPPrefix, fmt.Sprintf("%d-%d", rule.PortRangeMin, rule.PortRangeMax))
+		}
+
+		instance, floatingIP, owner, created := instanceInfoForSG(sg, ports, fips, serversByID)
+		checker.Findings = append(checker.Findings, Finding{
+			Kind:       "policy",
+			SGID:       sg.ID,
+			Tenant:     projectName,
+			Name:       sg.Name,
+			Region:     checker.RegionName,
+			Rule:       rulesDescription,
+			Instance:   instance,
+			FloatingIP: floatingIP,
+			Owner:      owner,
+			Created:    created,
+			Severity:   "warning",
 		})
-		attachment := slack.Attachment{
-			Color:  "#ff6347",
-			Fields: fields,
-		}
-		checker.Attachments = append(checker.Attachments, attachment)
 		return true, err
 	}
 	return false, err
@@ -523,6 +777,10 @@ func isPrivateIP(ip net.IP) (bool, error) {
 		"10.0.0.0/8",
 		"172.16.0.0/12",
 		"192.168.0.0/16",
+		"fc00::/7",
+		"fe80::/10",
+		"::1/128",
+		"::/128",
 	} {
 		_, block, err := net.ParseCIDR(cidr)
 		if err != nil {
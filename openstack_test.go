@@ -0,0 +1,61 @@
+package main
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/gophercloud/gophercloud/openstack/identity/v3/projects"
+)
+
+// TestResolveRuleTenantIDsPerRegion exercises the exact concurrency shape
+// Run() fans Cfg.Rules resolution out into: one goroutine per region,
+// each resolving the same base rules against its own region's project
+// list. Before chunk0-3's fix, the equivalent in-place loop mutated a
+// slice shared across every region's goroutine; this asserts that no
+// longer happens, both under -race and by checking the resolved
+// TenantIDs don't bleed between regions.
+func TestResolveRuleTenantIDsPerRegion(t *testing.T) {
+	base := []Rule{
+		{Tenant: "team-a", SG: "default"},
+		{Tenant: "team-b", SG: "default"},
+	}
+
+	regionProjects := map[string][]projects.Project{
+		"region-a": {{ID: "proj-a1", Name: "team-a"}, {ID: "proj-b1", Name: "team-b"}},
+		"region-b": {{ID: "proj-a2", Name: "team-a"}, {ID: "proj-b2", Name: "team-b"}},
+	}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	results := make(map[string][]Rule, len(regionProjects))
+	for region, ps := range regionProjects {
+		region, ps := region, ps
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resolved := resolveRuleTenantIDs(base, ps)
+			mu.Lock()
+			results[region] = resolved
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	wantTenantID := map[string]map[string]string{
+		"region-a": {"team-a": "proj-a1", "team-b": "proj-b1"},
+		"region-b": {"team-a": "proj-a2", "team-b": "proj-b2"},
+	}
+	for region, rules := range results {
+		for _, rule := range rules {
+			if got, want := rule.TenantID, wantTenantID[region][rule.Tenant]; got != want {
+				t.Errorf("%s: rule %s TenantID = %q, want %q", region, rule.Tenant, got, want)
+			}
+		}
+	}
+
+	for i, rule := range base {
+		if rule.TenantID != "" {
+			t.Errorf("resolveRuleTenantIDs mutated its input slice at index %d: TenantID = %q", i, rule.TenantID)
+		}
+	}
+}
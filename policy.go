@@ -0,0 +1,266 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/open-policy-agent/opa/rego"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// PolicySource prepares the rego query a policy evaluates against, hiding
+// whether the rego source comes from the local filesystem or a remote OPA
+// bundle. PreparedQuery is cheap to call repeatedly: implementations cache
+// the prepared query and only recompile when the underlying source changes.
+type PolicySource interface {
+	PreparedQuery(ctx context.Context) (rego.PreparedEvalQuery, error)
+	// Revision identifies the currently loaded policy version for decision
+	// logging (a bundle ETag/revision, or "" for local policies).
+	Revision() string
+}
+
+// LocalPolicySource is the original behavior: rego.Load from local
+// filesystem paths. The prepared query is compiled once and reused, since
+// local policy files aren't expected to change without a redeploy.
+type LocalPolicySource struct {
+	Paths []string
+
+	once  sync.Once
+	query rego.PreparedEvalQuery
+	err   error
+}
+
+func (s *LocalPolicySource) PreparedQuery(ctx context.Context) (rego.PreparedEvalQuery, error) {
+	s.once.Do(func() {
+		r := rego.New(
+			rego.Query("x = data.example.allow"),
+			rego.Load(s.Paths, nil),
+		)
+		s.query, s.err = r.PrepareForEval(ctx)
+	})
+	return s.query, s.err
+}
+
+func (s *LocalPolicySource) Revision() string {
+	return ""
+}
+
+// BundlePolicySource fetches an OPA bundle (.tar.gz) from BundleURL at most
+// once per PollInterval, sending the last ETag in If-None-Match, and only
+// re-prepares the query when the server answers 200 with a bundle rather
+// than 304 Not Modified. The bundle is loaded with rego.LoadBundle without
+// a verification key, so bundle signatures are not checked.
+type BundlePolicySource struct {
+	BundleURL    string
+	PollInterval time.Duration
+	BearerToken  string
+	TLSCertFile  string
+	TLSKeyFile   string
+
+	mu         sync.Mutex
+	httpClient *http.Client
+	etag       string
+	prepared   bool
+	query      rego.PreparedEvalQuery
+	lastPoll   time.Time
+}
+
+func (s *BundlePolicySource) PreparedQuery(ctx context.Context) (rego.PreparedEvalQuery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.prepared && time.Since(s.lastPoll) < s.PollInterval {
+		return s.query, nil
+	}
+
+	client, err := s.client()
+	if err != nil {
+		return s.query, errors.Wrapf(err, "Failed to build HTTP client for bundle %s", s.BundleURL)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.BundleURL, nil)
+	if err != nil {
+		return s.query, err
+	}
+	if s.BearerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+s.BearerToken)
+	}
+	if s.etag != "" {
+		req.Header.Set("If-None-Match", s.etag)
+	}
+
+	resp, err := client.Do(req)
+	// lastPoll advances regardless of outcome, so a sustained outage backs
+	// off to polling at PollInterval instead of retrying (and failing) on
+	// every single audit.
+	s.lastPoll = time.Now()
+	if err != nil {
+		if s.prepared {
+			logrus.WithError(err).Warnf("Failed to fetch bundle %s, keeping previously loaded policy", s.BundleURL)
+			return s.query, nil
+		}
+		return s.query, errors.Wrapf(err, "Failed to fetch bundle %s", s.BundleURL)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified {
+		return s.query, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		if s.prepared {
+			logrus.Warnf("Failed to fetch bundle %s: unexpected status %s, keeping previously loaded policy", s.BundleURL, resp.Status)
+			return s.query, nil
+		}
+		return s.query, fmt.Errorf("Failed to fetch bundle %s: unexpected status %s", s.BundleURL, resp.Status)
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return s.query, err
+	}
+
+	tmpFile, err := ioutil.TempFile("", "sg_inspector-bundle-*.tar.gz")
+	if err != nil {
+		return s.query, err
+	}
+	defer os.Remove(tmpFile.Name())
+	if _, err := tmpFile.Write(body); err != nil {
+		tmpFile.Close()
+		return s.query, err
+	}
+	tmpFile.Close()
+
+	s.query, err = rego.New(
+		rego.Query("x = data.example.allow"),
+		rego.LoadBundle(tmpFile.Name(), nil),
+	).PrepareForEval(ctx)
+	if err != nil {
+		return s.query, errors.Wrapf(err, "Failed to prepare bundle %s", s.BundleURL)
+	}
+
+	s.etag = resp.Header.Get("ETag")
+	s.prepared = true
+	return s.query, nil
+}
+
+func (s *BundlePolicySource) Revision() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.etag
+}
+
+func (s *BundlePolicySource) client() (*http.Client, error) {
+	if s.httpClient != nil {
+		return s.httpClient, nil
+	}
+
+	tlsConfig := &tls.Config{}
+	if s.TLSCertFile != "" && s.TLSKeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(s.TLSCertFile, s.TLSKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	s.httpClient = &http.Client{Transport: &http.Transport{TLSClientConfig: tlsConfig}}
+	return s.httpClient, nil
+}
+
+// DecisionLogEntry is a single rego evaluation, recorded so audits are
+// reproducible and policy rollouts can be correlated to the decisions they
+// produced.
+type DecisionLogEntry struct {
+	Timestamp      time.Time       `json:"timestamp"`
+	Input          json.RawMessage `json:"input"`
+	Decision       bool            `json:"decision"`
+	BundleRevision string          `json:"bundle_revision,omitempty"`
+}
+
+// DecisionLogger records decision log entries to a sink.
+type DecisionLogger interface {
+	Log(ctx context.Context, entry DecisionLogEntry) error
+}
+
+// StdoutDecisionLogger writes one JSON line per decision, for local runs and
+// CI where there's no central log collector.
+type StdoutDecisionLogger struct{}
+
+func (StdoutDecisionLogger) Log(ctx context.Context, entry DecisionLogEntry) error {
+	b, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(b))
+	return nil
+}
+
+// HTTPDecisionLogger posts decision log entries to a central collector.
+type HTTPDecisionLogger struct {
+	URL    string
+	Client *http.Client
+}
+
+func (l *HTTPDecisionLogger) Log(ctx context.Context, entry DecisionLogEntry) error {
+	body, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	client := l.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, l.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to post decision log to %s", l.URL)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("Failed to post decision log to %s: unexpected status %s", l.URL, resp.Status)
+	}
+	return nil
+}
+
+// policySourceFor builds the PolicySource for a policy, preferring a remote
+// OPA bundle when BundleURL is configured and falling back to the existing
+// local file behavior otherwise.
+func policySourceFor(policy Policy) PolicySource {
+	if policy.BundleURL != "" {
+		pollInterval := policy.BundlePollInterval
+		if pollInterval == 0 {
+			pollInterval = time.Minute
+		}
+		return &BundlePolicySource{
+			BundleURL:    policy.BundleURL,
+			PollInterval: pollInterval,
+			BearerToken:  policy.BundleToken,
+			TLSCertFile:  policy.BundleTLSCert,
+			TLSKeyFile:   policy.BundleTLSKey,
+		}
+	}
+
+	paths := []string{}
+	if policy.Policy != "" {
+		paths = append(paths, policy.Policy)
+	}
+	if policy.Data != "" {
+		paths = append(paths, policy.Data)
+	}
+	return &LocalPolicySource{Paths: paths}
+}
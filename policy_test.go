@@ -0,0 +1,156 @@
+package main
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/open-policy-agent/opa/rego"
+)
+
+const testPolicyAllow = `package example
+
+default allow = true
+`
+
+// buildTestBundle packages rego as a minimal unsigned OPA bundle tarball,
+// the same shape BundlePolicySource.PreparedQuery downloads and feeds to
+// rego.LoadBundle.
+func buildTestBundle(t *testing.T, policySource string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	body := []byte(policySource)
+	if err := tw.WriteHeader(&tar.Header{Name: "policy.rego", Mode: 0644, Size: int64(len(body))}); err != nil {
+		t.Fatalf("write tar header: %v", err)
+	}
+	if _, err := tw.Write(body); err != nil {
+		t.Fatalf("write tar body: %v", err)
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatalf("close tar writer: %v", err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatalf("close gzip writer: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func evalAllow(t *testing.T, query rego.PreparedEvalQuery) bool {
+	t.Helper()
+	rs, err := query.Eval(context.Background())
+	if err != nil {
+		t.Fatalf("Eval: %v", err)
+	}
+	if len(rs) == 0 {
+		t.Fatalf("Eval returned no results")
+	}
+	return rs[0].Bindings["x"].(bool)
+}
+
+func TestBundlePolicySourceLoadsOn200(t *testing.T) {
+	bundle := buildTestBundle(t, testPolicyAllow)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"rev-1"`)
+		w.Write(bundle)
+	}))
+	defer ts.Close()
+
+	src := &BundlePolicySource{BundleURL: ts.URL}
+	query, err := src.PreparedQuery(context.Background())
+	if err != nil {
+		t.Fatalf("PreparedQuery: %v", err)
+	}
+	if !evalAllow(t, query) {
+		t.Errorf("query decision = false, want true")
+	}
+	if got, want := src.Revision(), `"rev-1"`; got != want {
+		t.Errorf("Revision() = %q, want %q", got, want)
+	}
+}
+
+func TestBundlePolicySourceKeepsCachedQueryOn304(t *testing.T) {
+	bundle := buildTestBundle(t, testPolicyAllow)
+	requests := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			w.Header().Set("ETag", `"rev-1"`)
+			w.Write(bundle)
+			return
+		}
+		if got, want := r.Header.Get("If-None-Match"), `"rev-1"`; got != want {
+			t.Errorf("request %d If-None-Match = %q, want %q", requests, got, want)
+		}
+		w.WriteHeader(http.StatusNotModified)
+	}))
+	defer ts.Close()
+
+	src := &BundlePolicySource{BundleURL: ts.URL}
+	if _, err := src.PreparedQuery(context.Background()); err != nil {
+		t.Fatalf("first PreparedQuery: %v", err)
+	}
+
+	query, err := src.PreparedQuery(context.Background())
+	if err != nil {
+		t.Fatalf("second PreparedQuery: %v", err)
+	}
+	if requests != 2 {
+		t.Fatalf("server saw %d requests, want 2", requests)
+	}
+	if !evalAllow(t, query) {
+		t.Errorf("query decision after 304 = false, want true (cached query)")
+	}
+	if got, want := src.Revision(), `"rev-1"`; got != want {
+		t.Errorf("Revision() after 304 = %q, want %q", got, want)
+	}
+}
+
+func TestBundlePolicySourceFallsBackToCachedQueryOnFetchError(t *testing.T) {
+	bundle := buildTestBundle(t, testPolicyAllow)
+	up := true
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !up {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("ETag", `"rev-1"`)
+		w.Write(bundle)
+	}))
+	defer ts.Close()
+
+	src := &BundlePolicySource{BundleURL: ts.URL}
+	if _, err := src.PreparedQuery(context.Background()); err != nil {
+		t.Fatalf("first PreparedQuery: %v", err)
+	}
+	lastPollAfterSuccess := src.lastPoll
+
+	up = false
+	query, err := src.PreparedQuery(context.Background())
+	if err != nil {
+		t.Fatalf("PreparedQuery during outage should fall back to the cached policy, got error: %v", err)
+	}
+	if !evalAllow(t, query) {
+		t.Errorf("query decision during outage = false, want true (cached query)")
+	}
+	if !src.lastPoll.After(lastPollAfterSuccess) {
+		t.Errorf("lastPoll was not advanced by the failed fetch; polling won't back off during an outage")
+	}
+}
+
+func TestBundlePolicySourceFailsWhenNothingCachedYet(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer ts.Close()
+
+	src := &BundlePolicySource{BundleURL: ts.URL}
+	if _, err := src.PreparedQuery(context.Background()); err == nil {
+		t.Fatalf("PreparedQuery with no cached policy and a failing fetch should return an error")
+	}
+}
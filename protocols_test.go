@@ -0,0 +1,94 @@
+package main
+
+import (
+	"net"
+	"testing"
+)
+
+func TestFlaggedProtocolsDefaultsWhenUnset(t *testing.T) {
+	checker := &OpenStackSecurityGroupChecker{}
+	protocols, any := checker.flaggedProtocols()
+	if any {
+		t.Errorf("any = true, want false when Cfg.Protocols is unset")
+	}
+	want := []string{"tcp", "udp", "icmp", "icmpv6"}
+	if len(protocols) != len(want) {
+		t.Fatalf("protocols = %v, want %v", protocols, want)
+	}
+	for i, p := range want {
+		if protocols[i] != p {
+			t.Errorf("protocols[%d] = %q, want %q", i, protocols[i], p)
+		}
+	}
+}
+
+func TestFlaggedProtocolsEmptySliceFlagsEverything(t *testing.T) {
+	empty := []string{}
+	checker := &OpenStackSecurityGroupChecker{Cfg: Config{Protocols: &empty}}
+	protocols, any := checker.flaggedProtocols()
+	if !any {
+		t.Errorf("any = false, want true when Cfg.Protocols points at an empty slice")
+	}
+	if protocols != nil {
+		t.Errorf("protocols = %v, want nil when any is true", protocols)
+	}
+}
+
+func TestFlaggedProtocolsExplicitList(t *testing.T) {
+	only := []string{"tcp"}
+	checker := &OpenStackSecurityGroupChecker{Cfg: Config{Protocols: &only}}
+	protocols, any := checker.flaggedProtocols()
+	if any {
+		t.Errorf("any = true, want false for an explicit protocol list")
+	}
+	if len(protocols) != 1 || protocols[0] != "tcp" {
+		t.Errorf("protocols = %v, want [tcp]", protocols)
+	}
+}
+
+func TestIsWildcardRemote(t *testing.T) {
+	cases := []struct {
+		prefix string
+		want   bool
+	}{
+		{"0.0.0.0/0", true},
+		{"::/0", true},
+		{"10.0.0.0/8", false},
+		{"0.0.0.0/1", false},
+	}
+	for _, c := range cases {
+		if got := isWildcardRemote(c.prefix); got != c.want {
+			t.Errorf("isWildcardRemote(%q) = %v, want %v", c.prefix, got, c.want)
+		}
+	}
+}
+
+func TestIsPrivateIP(t *testing.T) {
+	cases := []struct {
+		ip   string
+		want bool
+	}{
+		{"10.1.2.3", true},
+		{"172.16.0.5", true},
+		{"192.168.1.1", true},
+		{"127.0.0.1", true},
+		{"8.8.8.8", false},
+		{"fc00::1", true},
+		{"fe80::1", true},
+		{"::1", true},
+		{"2001:db8::1", false},
+	}
+	for _, c := range cases {
+		ip := net.ParseIP(c.ip)
+		if ip == nil {
+			t.Fatalf("net.ParseIP(%q) returned nil", c.ip)
+		}
+		got, err := isPrivateIP(ip)
+		if err != nil {
+			t.Fatalf("isPrivateIP(%q): %v", c.ip, err)
+		}
+		if got != c.want {
+			t.Errorf("isPrivateIP(%q) = %v, want %v", c.ip, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+// Package server exposes sg_inspector's allowlist and audit functionality
+// over HTTP so it can run as a long-running service instead of a one-shot
+// batch job.
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// Entry is a single allow-listed security group along with the time
+// remaining before Redis expires it.
+type Entry struct {
+	ID  string        `json:"id"`
+	TTL time.Duration `json:"ttl"`
+}
+
+// AllowlistStore is the persistence backend for temporarily allow-listed
+// security groups. The HTTP handlers only depend on this interface so the
+// redis-backed implementation in package main can be swapped out in tests.
+type AllowlistStore interface {
+	Add(id string, ttl time.Duration) error
+	Remove(id string) error
+	List() ([]Entry, error)
+}
+
+// Checker runs a single audit pass and returns the findings it produced.
+// OpenStackSecurityGroupChecker implements this.
+type Checker interface {
+	Run() error
+	LastFindings() interface{}
+}
+
+// Server wires the allowlist store and checker up to a JSON HTTP API.
+type Server struct {
+	Allowlist AllowlistStore
+	Checker   Checker
+	router    *mux.Router
+
+	// auditMu serializes access to Checker: Run mutates the checker's audit
+	// state (findings, projects, rules) in place, and LastFindings reads it
+	// back, so an audit and a findings read (or two audits) racing on the
+	// same *http.Server would race on that state too. Handlers queue on
+	// this instead of running concurrently.
+	auditMu sync.Mutex
+}
+
+// New builds a Server ready to be handed to http.ListenAndServe.
+func New(allowlist AllowlistStore, checker Checker) *Server {
+	s := &Server{Allowlist: allowlist, Checker: checker}
+	s.router = mux.NewRouter()
+	s.router.HandleFunc("/v1/allowlist", s.handleAllowlistList).Methods(http.MethodGet)
+	s.router.HandleFunc("/v1/allowlist", s.handleAllowlistAdd).Methods(http.MethodPost)
+	s.router.HandleFunc("/v1/allowlist/{id}", s.handleAllowlistDelete).Methods(http.MethodDelete)
+	s.router.HandleFunc("/v1/audit", s.handleAudit).Methods(http.MethodPost)
+	s.router.HandleFunc("/v1/findings/latest", s.handleFindingsLatest).Methods(http.MethodGet)
+	return s
+}
+
+// ListenAndServe starts the embedded HTTP API on addr.
+func (s *Server) ListenAndServe(addr string) error {
+	logrus.Infof("Starting sg_inspector API server on %s", addr)
+	return http.ListenAndServe(addr, s.router)
+}
+
+type addAllowlistRequest struct {
+	ID        string `json:"id"`
+	TTLSecond int    `json:"ttl_seconds"`
+}
+
+func (s *Server) handleAllowlistAdd(w http.ResponseWriter, r *http.Request) {
+	var req addAllowlistRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, errors.Wrap(err, "Failed to decode request body"))
+		return
+	}
+	if req.ID == "" {
+		writeError(w, http.StatusBadRequest, errors.New("id is required"))
+		return
+	}
+	if req.TTLSecond < 0 {
+		writeError(w, http.StatusBadRequest, errors.New("ttl_seconds must not be negative"))
+		return
+	}
+	ttl := time.Duration(req.TTLSecond) * time.Second
+	if err := s.Allowlist.Add(req.ID, ttl); err != nil {
+		writeError(w, http.StatusInternalServerError, errors.Wrap(err, "Failed to add allowlist entry"))
+		return
+	}
+	writeJSON(w, http.StatusCreated, map[string]string{"id": req.ID})
+}
+
+func (s *Server) handleAllowlistDelete(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	if err := s.Allowlist.Remove(id); err != nil {
+		writeError(w, http.StatusInternalServerError, errors.Wrap(err, "Failed to remove allowlist entry"))
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleAllowlistList(w http.ResponseWriter, r *http.Request) {
+	entries, err := s.Allowlist.List()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, errors.Wrap(err, "Failed to list allowlist entries"))
+		return
+	}
+	writeJSON(w, http.StatusOK, entries)
+}
+
+func (s *Server) handleAudit(w http.ResponseWriter, r *http.Request) {
+	s.auditMu.Lock()
+	defer s.auditMu.Unlock()
+
+	if err := s.Checker.Run(); err != nil {
+		writeError(w, http.StatusInternalServerError, errors.Wrap(err, "Failed to run audit"))
+		return
+	}
+	writeJSON(w, http.StatusOK, s.Checker.LastFindings())
+}
+
+func (s *Server) handleFindingsLatest(w http.ResponseWriter, r *http.Request) {
+	s.auditMu.Lock()
+	defer s.auditMu.Unlock()
+
+	writeJSON(w, http.StatusOK, s.Checker.LastFindings())
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		logrus.WithError(err).Error("Failed to encode JSON response")
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	logrus.WithError(err).Error("sg_inspector API request failed")
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
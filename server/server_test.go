@@ -0,0 +1,132 @@
+package server
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeAllowlistStore is a minimal in-memory AllowlistStore for handler
+// tests that don't need a real Redis instance.
+type fakeAllowlistStore struct {
+	added map[string]time.Duration
+}
+
+func (s *fakeAllowlistStore) Add(id string, ttl time.Duration) error {
+	if s.added == nil {
+		s.added = map[string]time.Duration{}
+	}
+	s.added[id] = ttl
+	return nil
+}
+
+func (s *fakeAllowlistStore) Remove(id string) error {
+	delete(s.added, id)
+	return nil
+}
+
+func (s *fakeAllowlistStore) List() ([]Entry, error) {
+	entries := make([]Entry, 0, len(s.added))
+	for id, ttl := range s.added {
+		entries = append(entries, Entry{ID: id, TTL: ttl})
+	}
+	return entries, nil
+}
+
+// fakeChecker stands in for OpenStackSecurityGroupChecker: Run "audits" by
+// sleeping, so overlapping requests are observable, and records the high
+// water mark of concurrent Run/LastFindings calls via inFlight.
+type fakeChecker struct {
+	inFlight    int32
+	maxInFlight int32
+	findings    int32
+}
+
+func (c *fakeChecker) enter() func() {
+	n := atomic.AddInt32(&c.inFlight, 1)
+	for {
+		max := atomic.LoadInt32(&c.maxInFlight)
+		if n <= max || atomic.CompareAndSwapInt32(&c.maxInFlight, max, n) {
+			break
+		}
+	}
+	return func() { atomic.AddInt32(&c.inFlight, -1) }
+}
+
+func (c *fakeChecker) Run() error {
+	defer c.enter()()
+	time.Sleep(10 * time.Millisecond)
+	atomic.AddInt32(&c.findings, 1)
+	return nil
+}
+
+func (c *fakeChecker) LastFindings() interface{} {
+	defer c.enter()()
+	return atomic.LoadInt32(&c.findings)
+}
+
+// TestHandleAuditSerializesConcurrentRequests fires overlapping
+// POST /v1/audit and GET /v1/findings/latest requests and asserts they
+// queue on auditMu instead of running Run/LastFindings concurrently, which
+// would race on the real checker's Findings/Projects/Cfg.Rules.
+func TestHandleAuditSerializesConcurrentRequests(t *testing.T) {
+	checker := &fakeChecker{}
+	srv := New(nil, checker)
+	ts := httptest.NewServer(srv.router)
+	defer ts.Close()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			resp, err := http.Post(ts.URL+"/v1/audit", "application/json", nil)
+			if err != nil {
+				t.Errorf("POST /v1/audit: %v", err)
+				return
+			}
+			resp.Body.Close()
+		}()
+		go func() {
+			defer wg.Done()
+			resp, err := http.Get(ts.URL + "/v1/findings/latest")
+			if err != nil {
+				t.Errorf("GET /v1/findings/latest: %v", err)
+				return
+			}
+			resp.Body.Close()
+		}()
+	}
+	wg.Wait()
+
+	if max := atomic.LoadInt32(&checker.maxInFlight); max > 1 {
+		t.Errorf("max concurrent Run/LastFindings calls = %d, want 1 (handlers should serialize on auditMu)", max)
+	}
+}
+
+// TestHandleAllowlistAddRejectsNegativeTTL guards against a negative
+// ttl_seconds surfacing as an opaque error from the allowlist store (a
+// negative Redis expiration) instead of a clear 400.
+func TestHandleAllowlistAddRejectsNegativeTTL(t *testing.T) {
+	allowlist := &fakeAllowlistStore{}
+	srv := New(allowlist, &fakeChecker{})
+	ts := httptest.NewServer(srv.router)
+	defer ts.Close()
+
+	resp, err := http.Post(ts.URL+"/v1/allowlist", "application/json", bytes.NewBufferString(`{"id":"sg-1","ttl_seconds":-1}`))
+	if err != nil {
+		t.Fatalf("POST /v1/allowlist: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusBadRequest)
+	}
+	if _, ok := allowlist.added["sg-1"]; ok {
+		t.Errorf("negative ttl_seconds should not reach the allowlist store")
+	}
+}